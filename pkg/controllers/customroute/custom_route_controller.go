@@ -0,0 +1,118 @@
+package customroute
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+// DefaultRenewalWindow is how far ahead of a leaf certificate's expiry
+// validateServingCertKeyPairSecret starts rejecting it.
+const DefaultRenewalWindow = 30 * 24 * time.Hour
+
+// customRouteSecretDegradedCondition is the condition type set when a ComponentRoute's
+// servingCertKeyPairSecret is present but unusable.
+const customRouteSecretDegradedCondition = "CustomRouteSecretDegraded"
+
+// validateComponentRouteSecret checks the secret referenced by componentRoute, if any, and
+// returns the Degraded condition the customroute reconciler should set on the operator status.
+// A componentRoute with no servingCertKeyPairSecret, or one that references a secret which does
+// not exist, is not degraded: the route simply keeps serving its default certificate, matching
+// the existing "missing secret is silently ignored" behavior.
+func validateComponentRouteSecret(secretLister corev1listers.SecretLister, secretNamespace string, componentRoute configv1.ComponentRouteSpec) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   componentRoute.Name + customRouteSecretDegradedCondition,
+		Status: operatorv1.ConditionFalse,
+	}
+
+	if len(componentRoute.ServingCertKeyPairSecret.Name) == 0 {
+		return condition
+	}
+
+	secret, err := secretLister.Secrets(secretNamespace).Get(componentRoute.ServingCertKeyPairSecret.Name)
+	if apierrors.IsNotFound(err) {
+		return condition
+	}
+	if err != nil {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "SecretGetFailed"
+		condition.Message = err.Error()
+		return condition
+	}
+
+	if err := validateServingCertKeyPairSecret(secret, string(componentRoute.Hostname), DefaultRenewalWindow, nil); err != nil {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "InvalidServingCertKeyPairSecret"
+		condition.Message = err.Error()
+	}
+
+	return condition
+}
+
+// validateServingCertKeyPairSecret checks that secret contains a usable serving certificate for
+// hostname: the key and leaf certificate must parse and match, the leaf must not be within
+// renewalWindow of expiring, its SANs must cover hostname, and - when the leaf isn't self-signed
+// - it must chain to a trusted root, using any intermediates bundled alongside it in tls.crt plus
+// roots. Real serving-cert secrets only ever carry the leaf (and, optionally, intermediates) in
+// tls.crt, never the root, so roots is never built from the secret itself; passing nil verifies
+// against the system trust store.
+func validateServingCertKeyPairSecret(secret *corev1.Secret, hostname string, renewalWindow time.Duration, roots *x509.CertPool) error {
+	crtPEM, ok := secret.Data[corev1.TLSCertKey]
+	if !ok {
+		return fmt.Errorf("secret %s/%s is missing %q", secret.Namespace, secret.Name, corev1.TLSCertKey)
+	}
+	keyPEM, ok := secret.Data[corev1.TLSPrivateKeyKey]
+	if !ok {
+		return fmt.Errorf("secret %s/%s is missing %q", secret.Namespace, secret.Name, corev1.TLSPrivateKeyKey)
+	}
+
+	keyPair, err := tls.X509KeyPair(crtPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("secret %s/%s has a certificate and key that do not match: %v", secret.Namespace, secret.Name, err)
+	}
+
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("secret %s/%s has a leaf certificate that could not be parsed: %v", secret.Namespace, secret.Name, err)
+	}
+
+	if renewalWindow <= 0 {
+		renewalWindow = DefaultRenewalWindow
+	}
+	if time.Until(leaf.NotAfter) < renewalWindow {
+		return fmt.Errorf("secret %s/%s certificate expires %s, which is within the %s renewal window", secret.Namespace, secret.Name, leaf.NotAfter, renewalWindow)
+	}
+
+	if err := leaf.VerifyHostname(hostname); err != nil {
+		return fmt.Errorf("secret %s/%s certificate does not cover hostname %q: %v", secret.Namespace, secret.Name, hostname, err)
+	}
+
+	if bytes.Equal(leaf.RawIssuer, leaf.RawSubject) {
+		// self-signed leaf: nothing further to chain to
+		return nil
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, der := range keyPair.Certificate[1:] {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("secret %s/%s has a bundled certificate that could not be parsed: %v", secret.Namespace, secret.Name, err)
+		}
+		intermediates.AddCert(cert)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates}); err != nil {
+		return fmt.Errorf("secret %s/%s certificate does not chain to a trusted root: %v", secret.Namespace, secret.Name, err)
+	}
+
+	return nil
+}
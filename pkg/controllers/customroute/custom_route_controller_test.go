@@ -0,0 +1,204 @@
+package customroute
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func mustGenerateCert(t *testing.T, template, parent *x509.Certificate, signerKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signer := signerKey
+	signerCert := parent
+	if signer == nil {
+		signer = key
+		signerCert = template
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse generated certificate: %v", err)
+	}
+	return cert, key, der
+}
+
+func pemEncodeCert(ders ...[]byte) []byte {
+	var buf []byte
+	for _, der := range ders {
+		buf = append(buf, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	return buf
+}
+
+func pemEncodeKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func Test_validateServingCertKeyPairSecret(t *testing.T) {
+	now := time.Now()
+
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-root-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	rootCert, rootKey, _ := mustGenerateCert(t, rootTemplate, nil, nil)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCert)
+
+	intermediateTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "test-intermediate-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(60 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	intermediateCert, intermediateKey, intermediateDER := mustGenerateCert(t, intermediateTemplate, rootCert, rootKey)
+
+	validLeafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "valid.example.com"},
+		DNSNames:     []string{"valid.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(60 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	_, leafKey, leafDER := mustGenerateCert(t, validLeafTemplate, intermediateCert, intermediateKey)
+
+	expiredLeafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(4),
+		Subject:      pkix.Name{CommonName: "expired.example.com"},
+		DNSNames:     []string{"expired.example.com"},
+		NotBefore:    now.Add(-48 * time.Hour),
+		NotAfter:     now.Add(-time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	_, expiredKey, expiredDER := mustGenerateCert(t, expiredLeafTemplate, intermediateCert, intermediateKey)
+
+	wrongSANTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(5),
+		Subject:      pkix.Name{CommonName: "other.example.com"},
+		DNSNames:     []string{"other.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(60 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	_, wrongSANKey, wrongSANDER := mustGenerateCert(t, wrongSANTemplate, intermediateCert, intermediateKey)
+
+	mismatchedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate mismatched key: %v", err)
+	}
+
+	selfSignedTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(6),
+		Subject:      pkix.Name{CommonName: "self-signed.example.com"},
+		DNSNames:     []string{"self-signed.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(60 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	_, selfSignedKey, selfSignedDER := mustGenerateCert(t, selfSignedTemplate, nil, nil)
+
+	tests := []struct {
+		name     string
+		secret   *corev1.Secret
+		hostname string
+		wantErr  bool
+	}{
+		{
+			name: "valid chain and hostname",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       pemEncodeCert(leafDER, intermediateDER),
+					corev1.TLSPrivateKeyKey: pemEncodeKey(leafKey),
+				},
+			},
+			hostname: "valid.example.com",
+		},
+		{
+			name: "self-signed leaf needs no bundled root",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       pemEncodeCert(selfSignedDER),
+					corev1.TLSPrivateKeyKey: pemEncodeKey(selfSignedKey),
+				},
+			},
+			hostname: "self-signed.example.com",
+		},
+		{
+			name: "expired certificate",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       pemEncodeCert(expiredDER, intermediateDER),
+					corev1.TLSPrivateKeyKey: pemEncodeKey(expiredKey),
+				},
+			},
+			hostname: "expired.example.com",
+			wantErr:  true,
+		},
+		{
+			name: "hostname not covered by SANs",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       pemEncodeCert(wrongSANDER, intermediateDER),
+					corev1.TLSPrivateKeyKey: pemEncodeKey(wrongSANKey),
+				},
+			},
+			hostname: "requested.example.com",
+			wantErr:  true,
+		},
+		{
+			name: "missing intermediate bundle",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       pemEncodeCert(leafDER),
+					corev1.TLSPrivateKeyKey: pemEncodeKey(leafKey),
+				},
+			},
+			hostname: "valid.example.com",
+			wantErr:  true,
+		},
+		{
+			name: "key does not match leaf",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       pemEncodeCert(leafDER, intermediateDER),
+					corev1.TLSPrivateKeyKey: pemEncodeKey(mismatchedKey),
+				},
+			},
+			hostname: "valid.example.com",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.secret.Namespace = "openshift-config"
+			tt.secret.Name = "test-secret"
+			if err := validateServingCertKeyPairSecret(tt.secret, tt.hostname, DefaultRenewalWindow, roots); (err != nil) != tt.wantErr {
+				t.Errorf("validateServingCertKeyPairSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
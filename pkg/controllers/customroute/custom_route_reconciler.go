@@ -0,0 +1,137 @@
+package customroute
+
+import (
+	"context"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/errors"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configv1informers "github.com/openshift/client-go/config/informers/externalversions/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	routev1client "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// componentRouteSecretNamespace is where every ComponentRoute's servingCertKeyPairSecret lives.
+const componentRouteSecretNamespace = "openshift-config"
+
+// customRouteController reconciles the Ingress config's spec.componentRoutes into the
+// corresponding Route objects: applying the requested hostname and serving certificate when the
+// referenced secret validates, and leaving the route untouched otherwise, while surfacing *why*
+// via a per-component-route Degraded condition on the Authentication operator status.
+type customRouteController struct {
+	operatorClient v1helpers.OperatorClient
+	ingressLister  configv1listers.IngressLister
+	secretLister   corev1listers.SecretLister
+	routeClient    routev1client.RoutesGetter
+	tracker        *secretRefTracker
+}
+
+// NewCustomRouteController wires the controller to the Ingress config informer (for
+// ComponentRoute changes) and the openshift-config Secret informer, filtered through tracker so
+// that only secrets actually referenced by a ComponentRoute trigger a resync.
+func NewCustomRouteController(
+	operatorClient v1helpers.OperatorClient,
+	ingressInformer configv1informers.IngressInformer,
+	secretInformer corev1informers.SecretInformer,
+	routeClient routev1client.RoutesGetter,
+	recorder events.Recorder,
+) factory.Controller {
+	tracker := newSecretRefTracker()
+	c := &customRouteController{
+		operatorClient: operatorClient,
+		ingressLister:  ingressInformer.Lister(),
+		secretLister:   secretInformer.Lister(),
+		routeClient:    routeClient,
+		tracker:        tracker,
+	}
+	return factory.New().
+		WithSync(c.sync).
+		WithInformers(ingressInformer.Informer()).
+		WithFilteredEventsInformers(tracker.secretEventFilter(), secretInformer.Informer()).
+		ToController("CustomRouteController", recorder)
+}
+
+func (c *customRouteController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	ingressConfig, err := c.ingressLister.Get("cluster")
+	if err != nil {
+		return err
+	}
+
+	// Rebuild the secret->componentRoute tracking on every sync, so the Secret informer's event
+	// filter always reflects the current set of referenced secrets.
+	c.tracker.update(componentRouteSecretNamespace, ingressConfig.Spec.ComponentRoutes)
+
+	var errs []error
+	updateFuncs := make([]v1helpers.UpdateStatusFunc, 0, len(ingressConfig.Spec.ComponentRoutes))
+	for _, componentRoute := range ingressConfig.Spec.ComponentRoutes {
+		condition := validateComponentRouteSecret(c.secretLister, componentRouteSecretNamespace, componentRoute)
+		updateFuncs = append(updateFuncs, v1helpers.UpdateConditionFn(condition))
+
+		if err := c.applyComponentRoute(ctx, componentRoute, condition); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(updateFuncs) > 0 {
+		if _, _, err := v1helpers.UpdateStatus(ctx, c.operatorClient, updateFuncs...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.NewAggregate(errs)
+}
+
+// applyComponentRoute updates the Route named by componentRoute to the requested hostname and
+// serving certificate. When condition reports the secret as Degraded, the route is left
+// untouched rather than writing a broken certificate into it.
+func (c *customRouteController) applyComponentRoute(ctx context.Context, componentRoute configv1.ComponentRouteSpec, condition operatorv1.OperatorCondition) error {
+	if condition.Status == operatorv1.ConditionTrue {
+		return nil
+	}
+	if len(componentRoute.Hostname) == 0 {
+		return nil
+	}
+
+	route, err := c.routeClient.Routes(componentRoute.Namespace).Get(ctx, componentRoute.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	updated := route.DeepCopy()
+	updated.Spec.Host = string(componentRoute.Hostname)
+
+	if len(componentRoute.ServingCertKeyPairSecret.Name) > 0 {
+		secret, err := c.secretLister.Secrets(componentRouteSecretNamespace).Get(componentRoute.ServingCertKeyPairSecret.Name)
+		if apierrors.IsNotFound(err) {
+			// a referenced secret that does not exist leaves the route untouched
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if updated.Spec.TLS == nil {
+			updated.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
+		}
+		updated.Spec.TLS.Certificate = string(secret.Data[corev1.TLSCertKey])
+		updated.Spec.TLS.Key = string(secret.Data[corev1.TLSPrivateKeyKey])
+	}
+
+	if updated.Spec.Host == route.Spec.Host && reflect.DeepEqual(updated.Spec.TLS, route.Spec.TLS) {
+		return nil
+	}
+
+	_, err = c.routeClient.Routes(componentRoute.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
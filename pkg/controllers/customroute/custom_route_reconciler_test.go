@@ -0,0 +1,146 @@
+package customroute
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func Test_customRouteController_applyComponentRoute(t *testing.T) {
+	now := time.Now()
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "console.apps.example.com"},
+		DNSNames:     []string{"console.apps.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	_, leafKey, leafDER := mustGenerateCert(t, leafTemplate, nil, nil)
+
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	secretIndexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: "console-serving-cert"},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pemEncodeCert(leafDER),
+			corev1.TLSPrivateKeyKey: pemEncodeKey(leafKey),
+		},
+	})
+	secretLister := corev1listers.NewSecretLister(secretIndexer)
+
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-console", Name: "console"},
+		Spec:       routev1.RouteSpec{Host: "console-openshift-console.apps.example.com"},
+	}
+
+	tests := []struct {
+		name           string
+		condition      operatorv1.OperatorCondition
+		componentRoute configv1.ComponentRouteSpec
+		wantHost       string
+		wantTLS        bool
+	}{
+		{
+			name:      "valid secret applies hostname and certificate",
+			condition: operatorv1.OperatorCondition{Status: operatorv1.ConditionFalse},
+			componentRoute: configv1.ComponentRouteSpec{
+				Namespace:                "openshift-console",
+				Name:                     "console",
+				Hostname:                 "console.apps.example.com",
+				ServingCertKeyPairSecret: configv1.SecretNameReference{Name: "console-serving-cert"},
+			},
+			wantHost: "console.apps.example.com",
+			wantTLS:  true,
+		},
+		{
+			name:      "degraded condition leaves route untouched",
+			condition: operatorv1.OperatorCondition{Status: operatorv1.ConditionTrue},
+			componentRoute: configv1.ComponentRouteSpec{
+				Namespace:                "openshift-console",
+				Name:                     "console",
+				Hostname:                 "console.apps.example.com",
+				ServingCertKeyPairSecret: configv1.SecretNameReference{Name: "console-serving-cert"},
+			},
+			wantHost: "console-openshift-console.apps.example.com",
+			wantTLS:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routeClient := routefake.NewSimpleClientset(route.DeepCopy())
+			c := &customRouteController{
+				secretLister: secretLister,
+				routeClient:  routeClient.RouteV1(),
+			}
+
+			if err := c.applyComponentRoute(context.Background(), tt.componentRoute, tt.condition); err != nil {
+				t.Fatalf("applyComponentRoute() error = %v", err)
+			}
+
+			got, err := routeClient.RouteV1().Routes("openshift-console").Get(context.Background(), "console", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got.Spec.Host != tt.wantHost {
+				t.Errorf("Spec.Host = %q, want %q", got.Spec.Host, tt.wantHost)
+			}
+			if hasTLS := got.Spec.TLS != nil && len(got.Spec.TLS.Certificate) > 0; hasTLS != tt.wantTLS {
+				t.Errorf("route has TLS certificate = %v, want %v", hasTLS, tt.wantTLS)
+			}
+		})
+	}
+}
+
+// Test_customRouteController_sync_updatesTracker is the regression test for the bug the
+// secretRefTracker wiring fixes: every sync must refresh the tracker from the ingress config's
+// current ComponentRoutes, otherwise the Secret informer's event filter never sees new refs.
+func Test_customRouteController_sync_updatesTracker(t *testing.T) {
+	ingressIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	ingressIndexer.Add(&configv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: configv1.IngressSpec{
+			ComponentRoutes: []configv1.ComponentRouteSpec{
+				{
+					Namespace:                "openshift-console",
+					Name:                     "console",
+					ServingCertKeyPairSecret: configv1.SecretNameReference{Name: "console-serving-cert"},
+				},
+			},
+		},
+	})
+
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-console", Name: "console"}}
+
+	c := &customRouteController{
+		operatorClient: v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		ingressLister:  configv1listers.NewIngressLister(ingressIndexer),
+		secretLister:   corev1listers.NewSecretLister(secretIndexer),
+		routeClient:    routefake.NewSimpleClientset(route).RouteV1(),
+		tracker:        newSecretRefTracker(),
+	}
+
+	if err := c.sync(context.Background(), nil); err != nil {
+		t.Fatalf("sync() error = %v", err)
+	}
+
+	if !c.tracker.tracks("openshift-config", "console-serving-cert") {
+		t.Errorf("expected sync to update the tracker with the console ComponentRoute's secret ref")
+	}
+}
@@ -0,0 +1,83 @@
+package customroute
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+var suppressedSecretEvents = metrics.NewCounter(&metrics.CounterOpts{
+	Name: "cluster_authentication_operator_customroute_suppressed_secret_events_total",
+	Help: "Number of Secret add/update/delete events in openshift-config suppressed because the secret is not referenced by any ComponentRoute's servingCertKeyPairSecret.",
+})
+
+func init() {
+	legacyregistry.MustRegister(suppressedSecretEvents)
+}
+
+// secretRefTracker maps a secret's "namespace/name" to the set of ComponentRoute names that
+// currently reference it as their servingCertKeyPairSecret. It is rebuilt on every
+// ingress-config sync and consulted by the Secret informer's event filter, so unrelated secret
+// churn in openshift-config - there are typically dozens of unrelated TLS secrets there - never
+// triggers a customroute reconcile.
+type secretRefTracker struct {
+	mu   sync.RWMutex
+	refs map[string]sets.String
+}
+
+func newSecretRefTracker() *secretRefTracker {
+	return &secretRefTracker{refs: map[string]sets.String{}}
+}
+
+// update rebuilds the tracker from the ComponentRoutes currently set on the ingress config.
+func (t *secretRefTracker) update(secretNamespace string, componentRoutes []configv1.ComponentRouteSpec) {
+	refs := map[string]sets.String{}
+	for _, componentRoute := range componentRoutes {
+		if len(componentRoute.ServingCertKeyPairSecret.Name) == 0 {
+			continue
+		}
+		key := secretRefKey(secretNamespace, componentRoute.ServingCertKeyPairSecret.Name)
+		if refs[key] == nil {
+			refs[key] = sets.NewString()
+		}
+		refs[key].Insert(componentRoute.Name)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refs = refs
+}
+
+// tracks reports whether namespace/name is currently referenced by any ComponentRoute.
+func (t *secretRefTracker) tracks(namespace, name string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	_, ok := t.refs[secretRefKey(namespace, name)]
+	return ok
+}
+
+func secretRefKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// secretEventFilter returns an informer event filter func that only lets a Secret event through
+// when the tracker currently has a ComponentRoute referencing it. Non-Secret objects (the
+// informer's initial sync sentinel, for example) always pass through unfiltered.
+func (t *secretRefTracker) secretEventFilter() func(obj interface{}) bool {
+	return func(obj interface{}) bool {
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return true
+		}
+		if t.tracks(secret.Namespace, secret.Name) {
+			return true
+		}
+		suppressedSecretEvents.Inc()
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+package customroute
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func Test_secretRefTracker_secretEventFilter(t *testing.T) {
+	tracker := newSecretRefTracker()
+	tracker.update("openshift-config", []configv1.ComponentRouteSpec{
+		{
+			Name: "oauth-openshift",
+			ServingCertKeyPairSecret: configv1.SecretNameReference{
+				Name: "oauth-serving-cert",
+			},
+		},
+	})
+	filter := tracker.secretEventFilter()
+
+	tests := []struct {
+		name   string
+		secret *corev1.Secret
+		want   bool
+	}{
+		{
+			name:   "tracked secret",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: "oauth-serving-cert"}},
+			want:   true,
+		},
+		{
+			name:   "unrelated secret in the same namespace",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: "some-other-tls-secret"}},
+			want:   false,
+		},
+		{
+			name:   "tracked name in a different namespace",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "other-namespace", Name: "oauth-serving-cert"}},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter(tt.secret); got != tt.want {
+				t.Errorf("secretEventFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_secretRefTracker_secretEventFilter_suppressesChurn is the regression test for the bug
+// this filter exists to fix: a realistic pile of unrelated openshift-config TLS secrets must
+// not pass the filter, only the handful actually referenced by a ComponentRoute.
+func Test_secretRefTracker_secretEventFilter_suppressesChurn(t *testing.T) {
+	tracker := newSecretRefTracker()
+	tracker.update("openshift-config", []configv1.ComponentRouteSpec{
+		{
+			Name:                     "oauth-openshift",
+			ServingCertKeyPairSecret: configv1.SecretNameReference{Name: "oauth-serving-cert"},
+		},
+		{
+			Name:                     "console",
+			ServingCertKeyPairSecret: configv1.SecretNameReference{Name: "console-serving-cert"},
+		},
+	})
+	filter := tracker.secretEventFilter()
+
+	passed := 0
+	for i := 0; i < 50; i++ {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: "unrelated-tls-secret"}}
+		if filter(secret) {
+			passed++
+		}
+	}
+	if passed != 0 {
+		t.Errorf("expected none of the 50 unrelated secret events to pass the filter, got %d", passed)
+	}
+
+	for _, name := range []string{"oauth-serving-cert", "console-serving-cert"} {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: name}}
+		if !filter(secret) {
+			t.Errorf("expected tracked secret %q to pass the filter", name)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package endpointaccessible
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	// backoffInitial is the backoff duration after an endpoint's first consecutive failure.
+	backoffInitial = time.Second
+	// backoffFactor is the multiplier applied to the backoff duration on each further failure.
+	backoffFactor = 2.0
+	// backoffMax caps how long a flapping endpoint is left alone between probes.
+	backoffMax = 5 * time.Minute
+	// backoffJitterFraction is how far, as a fraction of the computed duration, the jitter may
+	// push the actual backoff in either direction.
+	backoffJitterFraction = 0.2
+)
+
+// EndpointListFunc returns a list of endpoints to probe for availability. What counts as an
+// "endpoint" depends on the Prober in use: a bare host or full URL for the HTTPS-healthz prober
+// (see toHealthzURL), a "host:port" pair for the TCP prober, or an issuer base URL for the
+// OIDC-discovery prober.
+type EndpointListFunc func() ([]string, error)
+
+// Prober knows how to check a single endpoint and report whether it is currently accessible.
+// endpointAccessibleController treats a non-nil error as "this endpoint is down".
+type Prober interface {
+	Probe(ctx context.Context, endpoint string) error
+}
+
+// endpointBackoffState tracks the exponential-backoff schedule for a single flapping endpoint.
+type endpointBackoffState struct {
+	failures     int
+	failingSince time.Time
+	nextAttempt  time.Time
+	lastErr      error
+}
+
+// inBackoff reports whether the endpoint has an active backoff window at all.
+func (s *endpointBackoffState) inBackoff() bool {
+	return s.failures > 0
+}
+
+// endpointAccessibleController periodically probes a list of endpoints and goes degraded when
+// any of them are not reachable. A probe that fails repeatedly is backed off exponentially so a
+// flapping endpoint doesn't pin the controller into a tight requeue loop.
+type endpointAccessibleController struct {
+	endpointListFn EndpointListFunc
+	prober         Prober
+
+	backoffMu sync.Mutex
+	backoff   map[string]*endpointBackoffState
+
+	// now and jitter are overridden in tests to make the backoff schedule deterministic.
+	now    func() time.Time
+	jitter func() float64
+}
+
+// EndpointAccessibleControllerOption customizes a controller built by NewEndpointAccessibleController.
+// Keeping prober selection an option (rather than a positional constructor argument) means
+// existing call sites that only know about the original HTTPS-healthz behavior keep compiling
+// unchanged.
+type EndpointAccessibleControllerOption func(*endpointAccessibleController)
+
+// WithProber overrides the default HTTPS-healthz prober, e.g. with a tcpProber or
+// oidcDiscoveryProber for endpoints that don't speak /healthz.
+func WithProber(prober Prober) EndpointAccessibleControllerOption {
+	return func(c *endpointAccessibleController) {
+		c.prober = prober
+	}
+}
+
+// NewEndpointAccessibleController returns a controller that periodically probes endpointListFn's
+// endpoints. By default it uses the HTTPS-healthz prober; pass WithProber to use a different one.
+func NewEndpointAccessibleController(
+	name string,
+	operatorClient v1helpers.OperatorClient,
+	endpointListFn EndpointListFunc,
+	triggers []factory.Informer,
+	recorder events.Recorder,
+	options ...EndpointAccessibleControllerOption,
+) factory.Controller {
+	c := &endpointAccessibleController{
+		endpointListFn: endpointListFn,
+		prober:         &httpsHealthzProber{},
+		backoff:        map[string]*endpointBackoffState{},
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return factory.New().
+		WithSync(c.sync).
+		WithInformers(triggers...).
+		WithSyncDegradedOnError(operatorClient).
+		ResyncEvery(30*time.Second).
+		ToController(name+"EndpointAccessibleController", recorder)
+}
+
+func (c *endpointAccessibleController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	endpoints, err := c.endpointListFn()
+	if err != nil {
+		return err
+	}
+
+	prober := c.prober
+	if prober == nil {
+		prober = &httpsHealthzProber{}
+	}
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		if err := c.probeWithBackoff(ctx, syncCtx.Recorder(), prober, endpoint); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.NewAggregate(errs)
+}
+
+func (c *endpointAccessibleController) nowFn() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func (c *endpointAccessibleController) jitterFn() float64 {
+	if c.jitter != nil {
+		return c.jitter()
+	}
+	return rand.Float64()
+}
+
+func (c *endpointAccessibleController) backoffState(endpoint string) *endpointBackoffState {
+	c.backoffMu.Lock()
+	defer c.backoffMu.Unlock()
+	if c.backoff == nil {
+		c.backoff = map[string]*endpointBackoffState{}
+	}
+	state, ok := c.backoff[endpoint]
+	if !ok {
+		state = &endpointBackoffState{}
+		c.backoff[endpoint] = state
+	}
+	return state
+}
+
+// probeWithBackoff probes endpoint unless it is currently within its backoff window, in which
+// case the probe is skipped and the endpoint is treated as "still failing, no state change".
+func (c *endpointAccessibleController) probeWithBackoff(ctx context.Context, recorder events.Recorder, prober Prober, endpoint string) error {
+	now := c.nowFn()
+	state := c.backoffState(endpoint)
+
+	if state.inBackoff() && now.Before(state.nextAttempt) {
+		return fmt.Errorf("%q has been failing for %s, still in backoff for %s more: %v",
+			endpoint, now.Sub(state.failingSince).Round(time.Second), state.nextAttempt.Sub(now).Round(time.Second), state.lastErr)
+	}
+
+	probeErr := prober.Probe(ctx, endpoint)
+
+	wasInBackoff := state.inBackoff()
+	if probeErr == nil {
+		c.backoffMu.Lock()
+		*state = endpointBackoffState{}
+		c.backoffMu.Unlock()
+		if wasInBackoff {
+			recorder.Eventf("EndpointBackoffCleared", "endpoint %q recovered, leaving backoff", endpoint)
+		}
+		return nil
+	}
+
+	c.backoffMu.Lock()
+	state.failures++
+	if !wasInBackoff {
+		state.failingSince = now
+	}
+	duration := backoffDuration(state.failures, c.jitterFn())
+	state.nextAttempt = now.Add(duration)
+	state.lastErr = probeErr
+	c.backoffMu.Unlock()
+
+	if !wasInBackoff {
+		recorder.Eventf("EndpointBackoffStarted", "endpoint %q failed, entering backoff for %s", endpoint, duration.Round(time.Second))
+	}
+
+	return fmt.Errorf("%q has been failing for %s, backing off for %s: %v",
+		endpoint, now.Sub(state.failingSince).Round(time.Second), duration.Round(time.Second), probeErr)
+}
+
+// backoffDuration computes the exponential-with-jitter backoff for the given consecutive-failure
+// count: 1s, factor 2, capped at 5m. jitter is a random value in [0,1) that is scaled to
+// +/-20% of the unjittered duration.
+func backoffDuration(failures int, jitter float64) time.Duration {
+	unjittered := float64(backoffInitial) * math.Pow(backoffFactor, float64(failures-1))
+	// map jitter in [0,1) to a multiplier in [1-backoffJitterFraction, 1+backoffJitterFraction)
+	multiplier := 1 - backoffJitterFraction + jitter*2*backoffJitterFraction
+	jittered := unjittered * multiplier
+	// clamp after jitter is applied, so the jitter can never push the actual backoff past
+	// backoffMax.
+	if jittered > float64(backoffMax) {
+		jittered = float64(backoffMax)
+	}
+	return time.Duration(jittered)
+}
+
+// TLSConfigFn returns the CA bundle and accepted server name(s) that a probed endpoint's
+// certificate chain is expected to validate against. When unset, httpsHealthzProber skips
+// certificate verification altogether (InsecureSkipVerify) and only looks at the response status.
+type TLSConfigFn func() (*x509.CertPool, []string, error)
+
+// httpsHealthzProber is the original Prober implementation: it issues a GET against the
+// endpoint and treats any non-2xx response as a failure. endpoint may be a bare host (in which
+// case toHealthzURL builds "https://<host>/healthz" out of it) or an already-complete URL.
+type httpsHealthzProber struct {
+	// TLSConfigFn, when set, causes Probe to validate the probed server's certificate chain
+	// against the returned CA pool and to reject certificates whose SANs don't include the
+	// returned server name(s).
+	TLSConfigFn TLSConfigFn
+}
+
+func (p *httpsHealthzProber) Probe(ctx context.Context, endpoint string) error {
+	target := endpoint
+	if !strings.Contains(endpoint, "://") {
+		target = toHealthzURL([]string{endpoint})[0]
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("failed to parse endpoint %q: %v", target, err)
+	}
+
+	var rootCAs *x509.CertPool
+	var serverNames []string
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if p.TLSConfigFn != nil {
+		rootCAs, serverNames, err = p.TLSConfigFn()
+		if err != nil {
+			return fmt.Errorf("failed to build TLS config for endpoint probe: %v", err)
+		}
+		tlsConfig = &tls.Config{
+			RootCAs:            rootCAs,
+			InsecureSkipVerify: false,
+			ServerName:         u.Hostname(),
+		}
+	}
+
+	httpClient := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request for %q: %v", target, err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to GET %q: %v", target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%q returned %q", target, resp.Status)
+	}
+
+	if p.TLSConfigFn != nil {
+		if err := verifyServedChain(resp, u.Hostname(), serverNames); err != nil {
+			return fmt.Errorf("%q failed certificate verification: %v", target, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyServedChain re-checks the chain the server actually presented (as recorded by the
+// stdlib verifier in resp.TLS.VerifiedChains) against acceptedNames - or, when acceptedNames is
+// empty, against the hostname we dialed - so a cert whose SANs cover none of those names, but
+// which happens to chain to a CA we trust, is still rejected.
+func verifyServedChain(resp *http.Response, dialedHost string, acceptedNames []string) error {
+	if resp.TLS == nil || len(resp.TLS.VerifiedChains) == 0 {
+		return fmt.Errorf("no verified certificate chain presented")
+	}
+
+	names := acceptedNames
+	if len(names) == 0 {
+		names = []string{dialedHost}
+	}
+
+	for _, chain := range resp.TLS.VerifiedChains {
+		if len(chain) == 0 {
+			continue
+		}
+		leaf := chain[0]
+		for _, name := range names {
+			if leaf.VerifyHostname(name) == nil {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("served certificate does not match any of %v", names)
+}
+
+// toHealthzURL builds the probe targets httpsHealthzProber.Probe uses for bare endpoint hosts
+// (endpoints that are already a full URL are left untouched).
+func toHealthzURL(endpoints []string) []string {
+	ret := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		ret = append(ret, fmt.Sprintf("https://%s/healthz", endpoint))
+	}
+	return ret
+}
+
+// tcpProber opens a plain TCP connection to the endpoint ("host:port") and closes it again.
+// Useful for upstreams such as LDAP that don't expose an HTTP healthz path.
+type tcpProber struct {
+	Timeout time.Duration
+}
+
+func (p *tcpProber) Probe(ctx context.Context, endpoint string) error {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %q: %v", endpoint, err)
+	}
+	return conn.Close()
+}
+
+// oidcDiscoveryMetadata is the subset of the OIDC discovery document
+// (https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata) oidcDiscoveryProber
+// cares about.
+type oidcDiscoveryMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcDiscoveryProber fetches the endpoint's "/.well-known/openid-configuration" document and
+// checks that issuer, authorization_endpoint and jwks_uri are all non-empty and that jwks_uri
+// answers a GET with 200. The issuer and authorization_endpoint URLs are not requested.
+type oidcDiscoveryProber struct {
+	Timeout time.Duration
+}
+
+func (p *oidcDiscoveryProber) Probe(ctx context.Context, endpoint string) error {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	httpClient := &http.Client{Timeout: timeout}
+
+	discoveryURL := strings.TrimSuffix(endpoint, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request for %q: %v", discoveryURL, err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to GET %q: %v", discoveryURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%q returned %q", discoveryURL, resp.Status)
+	}
+
+	var metadata oidcDiscoveryMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return fmt.Errorf("%q returned a malformed discovery document: %v", discoveryURL, err)
+	}
+
+	if metadata.Issuer == "" || metadata.AuthorizationEndpoint == "" || metadata.JWKSURI == "" {
+		return fmt.Errorf("%q discovery document is missing issuer, authorization_endpoint or jwks_uri", discoveryURL)
+	}
+
+	jwksReq, err := http.NewRequestWithContext(ctx, http.MethodGet, metadata.JWKSURI, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request for jwks_uri %q: %v", metadata.JWKSURI, err)
+	}
+	jwksResp, err := httpClient.Do(jwksReq)
+	if err != nil {
+		return fmt.Errorf("jwks_uri %q is not reachable: %v", metadata.JWKSURI, err)
+	}
+	defer jwksResp.Body.Close()
+	if jwksResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks_uri %q returned %q", metadata.JWKSURI, jwksResp.Status)
+	}
+
+	return nil
+}
@@ -2,14 +2,56 @@ package endpointaccessible
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 )
 
+// mustSelfSignedCert generates a self-signed, CA:true certificate so it can double as its own
+// trust anchor in tests, with the given DNS SANs.
+func mustSelfSignedCert(t *testing.T, commonName string, dnsNames ...string) tls.Certificate {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              dnsNames,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse generated certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
 func Test_endpointAccessibleController_sync(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -56,6 +98,361 @@ func Test_endpointAccessibleController_sync(t *testing.T) {
 	}
 }
 
+// mustSelfSignedCertForIP is like mustSelfSignedCert but grants the cert an IP SAN instead of a
+// DNS SAN. httptest.Server is dialed by IP, so Probe's ServerName (the dialed host) is an IP
+// literal: without an IP SAN the handshake itself would reject the cert before verifyServedChain
+// - the custom re-check under test - ever runs.
+func mustSelfSignedCertForIP(t *testing.T, commonName string, ip net.IP) tls.Certificate {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		IPAddresses:           []net.IP{ip},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse generated certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func Test_endpointAccessibleController_sync_tlsVerification(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	// server presents a genuine cert for "wrong.example.com" regardless of the hostname we dial
+	// it as, so acceptedNames naming a different host must fail hostname verification.
+	mismatchedSANServer := httptest.NewUnstartedServer(okHandler)
+	mismatchedSANServer.TLS = &tls.Config{Certificates: []tls.Certificate{mustSelfSignedCert(t, "wrong.example.com", "wrong.example.com")}}
+	mismatchedSANServer.StartTLS()
+	defer mismatchedSANServer.Close()
+
+	untrustedCAServer := httptest.NewTLSServer(okHandler)
+	defer untrustedCAServer.Close()
+
+	// server presents a cert covering the IP we actually dial it on, so the handshake's own
+	// hostname check passes; only the custom acceptedNames re-check can catch a mismatch here.
+	ipSANServer := httptest.NewUnstartedServer(okHandler)
+	ipSANServer.TLS = &tls.Config{Certificates: []tls.Certificate{mustSelfSignedCertForIP(t, "ip-san.example.com", net.ParseIP("127.0.0.1"))}}
+	ipSANServer.StartTLS()
+	defer ipSANServer.Close()
+
+	tests := []struct {
+		name        string
+		server      *httptest.Server
+		tlsConfigFn TLSConfigFn
+		wantErr     bool
+	}{
+		{
+			name:   "trusted CA and matching SAN",
+			server: untrustedCAServer,
+			tlsConfigFn: func() (*x509.CertPool, []string, error) {
+				pool := x509.NewCertPool()
+				pool.AddCert(untrustedCAServer.Certificate())
+				return pool, []string{untrustedCAServer.Certificate().DNSNames[0]}, nil
+			},
+		},
+		{
+			name:   "untrusted CA",
+			server: untrustedCAServer,
+			tlsConfigFn: func() (*x509.CertPool, []string, error) {
+				// empty pool never trusts the server's self-signed cert
+				return x509.NewCertPool(), []string{untrustedCAServer.Certificate().DNSNames[0]}, nil
+			},
+			wantErr: true,
+		},
+		{
+			name:   "SAN does not match accepted server names",
+			server: mismatchedSANServer,
+			tlsConfigFn: func() (*x509.CertPool, []string, error) {
+				pool := x509.NewCertPool()
+				pool.AddCert(mismatchedSANServer.Certificate())
+				return pool, []string{"totally-different-name.example.com"}, nil
+			},
+			wantErr: true,
+		},
+		{
+			// The stdlib handshake already accepts this cert for the dialed IP, so unlike the
+			// "SAN does not match" case above, this failure can only come from verifyServedChain
+			// re-checking acceptedNames against the served leaf.
+			name:   "dialed host covered by cert but accepted names mismatch",
+			server: ipSANServer,
+			tlsConfigFn: func() (*x509.CertPool, []string, error) {
+				pool := x509.NewCertPool()
+				pool.AddCert(ipSANServer.Certificate())
+				return pool, []string{"totally-different-name.example.com"}, nil
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &endpointAccessibleController{
+				endpointListFn: func() ([]string, error) {
+					return []string{tt.server.URL}, nil
+				},
+				prober: &httpsHealthzProber{TLSConfigFn: tt.tlsConfigFn},
+			}
+			if err := c.sync(context.Background(), factory.NewSyncContext(tt.name, events.NewInMemoryRecorder(tt.name))); (err != nil) != tt.wantErr {
+				t.Errorf("sync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_httpsHealthzProber_Probe_bareHost(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// server.URL is "https://127.0.0.1:PORT"; strip the scheme so Probe gets a bare "host:port"
+	// and has to build "https://host:port/healthz" itself via toHealthzURL.
+	bareHost := strings.TrimPrefix(server.URL, "https://")
+
+	p := &httpsHealthzProber{}
+	if err := p.Probe(context.Background(), bareHost); err != nil {
+		t.Errorf("Probe() with bare host %q failed: %v", bareHost, err)
+	}
+}
+
+func Test_tcpProber_Probe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to set up listener: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		wantErr  bool
+	}{
+		{
+			name:     "connection succeeds",
+			endpoint: listener.Addr().String(),
+		},
+		{
+			name:     "connection refused",
+			endpoint: "127.0.0.1:1",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &tcpProber{Timeout: time.Second}
+			if err := p.Probe(context.Background(), tt.endpoint); (err != nil) != tt.wantErr {
+				t.Errorf("Probe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_oidcDiscoveryProber_Probe(t *testing.T) {
+	mux := http.NewServeMux()
+	validServer := httptest.NewServer(mux)
+	defer validServer.Close()
+	// jwks_uri points back at this same test server so the "reachable" check succeeds end-to-end
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscoveryMetadata{
+			Issuer:                validServer.URL,
+			AuthorizationEndpoint: validServer.URL + "/auth",
+			JWKSURI:               validServer.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	missingFieldsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscoveryMetadata{Issuer: "https://issuer.example.com"})
+	}))
+	defer missingFieldsServer.Close()
+
+	malformedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer malformedServer.Close()
+
+	connectionRefusedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to set up listener: %v", err)
+	}
+	connectionRefusedAddr := "http://" + connectionRefusedListener.Addr().String()
+	connectionRefusedListener.Close()
+
+	// oidcDiscoveryProber uses a plain http.Client with no custom TLS config, so an untrusted
+	// server certificate is rejected by the default system trust store.
+	untrustedTLSServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer untrustedTLSServer.Close()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		wantErr  bool
+	}{
+		{
+			name:     "valid discovery document with reachable jwks_uri",
+			endpoint: validServer.URL,
+		},
+		{
+			name:     "connection refused",
+			endpoint: connectionRefusedAddr,
+			wantErr:  true,
+		},
+		{
+			name:     "TLS error",
+			endpoint: untrustedTLSServer.URL,
+			wantErr:  true,
+		},
+		{
+			name:     "missing required fields",
+			endpoint: missingFieldsServer.URL,
+			wantErr:  true,
+		},
+		{
+			name:     "malformed JSON",
+			endpoint: malformedServer.URL,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &oidcDiscoveryProber{Timeout: time.Second}
+			if err := p.Probe(context.Background(), tt.endpoint); (err != nil) != tt.wantErr {
+				t.Errorf("Probe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// sequenceProber returns results[0] on the first Probe call, results[1] on the second, and so
+// on, holding on the last entry once exhausted. It lets tests script "fails a few times, then
+// recovers" without depending on wall-clock timeouts.
+type sequenceProber struct {
+	mu      sync.Mutex
+	results []error
+	calls   int
+}
+
+func (p *sequenceProber) Probe(ctx context.Context, endpoint string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	idx := p.calls
+	if idx >= len(p.results) {
+		idx = len(p.results) - 1
+	}
+	p.calls++
+	return p.results[idx]
+}
+
+func Test_endpointAccessibleController_backoff(t *testing.T) {
+	clock := time.Unix(0, 0)
+	prober := &sequenceProber{results: []error{
+		fmt.Errorf("down"),
+		fmt.Errorf("still down"),
+		nil,
+	}}
+	c := &endpointAccessibleController{
+		endpointListFn: func() ([]string, error) { return []string{"https://flapping.example.com"}, nil },
+		prober:         prober,
+		backoff:        map[string]*endpointBackoffState{},
+		now:            func() time.Time { return clock },
+		jitter:         func() float64 { return 0.5 }, // no-op jitter: multiplier == 1
+	}
+	ctx := context.Background()
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	type step struct {
+		advance     time.Duration
+		wantAttempt bool
+		wantSyncErr bool
+	}
+	steps := []step{
+		{advance: 0, wantAttempt: true, wantSyncErr: true},                       // 1st failure, backoff = 1s
+		{advance: 500 * time.Millisecond, wantAttempt: false, wantSyncErr: true}, // still within 1s backoff
+		{advance: 600 * time.Millisecond, wantAttempt: true, wantSyncErr: true},  // t=1.1s, past backoff; 2nd failure, backoff = 2s
+		{advance: 400 * time.Millisecond, wantAttempt: false, wantSyncErr: true}, // t=1.5s, within 2s backoff
+		{advance: 2 * time.Second, wantAttempt: true, wantSyncErr: false},        // t=3.5s, past backoff; succeeds
+	}
+
+	attempted, skipped := 0, 0
+	for i, s := range steps {
+		clock = clock.Add(s.advance)
+		callsBefore := prober.calls
+		err := c.sync(ctx, syncCtx)
+		didAttempt := prober.calls > callsBefore
+		if didAttempt != s.wantAttempt {
+			t.Errorf("step %d: attempted = %v, want %v", i, didAttempt, s.wantAttempt)
+		}
+		if didAttempt {
+			attempted++
+		} else {
+			skipped++
+		}
+		if (err != nil) != s.wantSyncErr {
+			t.Errorf("step %d: sync() error = %v, wantErr %v", i, err, s.wantSyncErr)
+		}
+	}
+
+	if attempted != 3 {
+		t.Errorf("expected 3 attempted probes, got %d", attempted)
+	}
+	if skipped != 2 {
+		t.Errorf("expected 2 skipped probes, got %d", skipped)
+	}
+}
+
+func Test_backoffDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		jitter   float64
+		want     time.Duration
+	}{
+		{name: "first failure, no jitter", failures: 1, jitter: 0.5, want: time.Second},
+		{name: "second failure, no jitter", failures: 2, jitter: 0.5, want: 2 * time.Second},
+		{name: "first failure, max negative jitter", failures: 1, jitter: 0, want: 800 * time.Millisecond},
+		{name: "first failure, max positive jitter", failures: 1, jitter: 1, want: 1200 * time.Millisecond},
+		{name: "capped at backoffMax", failures: 30, jitter: 0.5, want: backoffMax},
+		{name: "capped at backoffMax even with max positive jitter", failures: 30, jitter: 1, want: backoffMax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backoffDuration(tt.failures, tt.jitter); got != tt.want {
+				t.Errorf("backoffDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_toHealthzURL(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,46 @@
+package endpointaccessible
+
+import (
+	"fmt"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// ExampleWithProber_tcp shows how an LDAP-style endpoint that doesn't speak HTTP is wired up:
+// WithProber swaps in a tcpProber instead of the default HTTPS-healthz one.
+func ExampleWithProber_tcp() {
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+
+	controller := NewEndpointAccessibleController(
+		"LDAPServer",
+		operatorClient,
+		func() ([]string, error) { return []string{"ldap.example.com:636"}, nil },
+		nil,
+		events.NewInMemoryRecorder("LDAPServerEndpointAccessibleController"),
+		WithProber(&tcpProber{}),
+	)
+
+	fmt.Println(controller.Name())
+	// Output: LDAPServerEndpointAccessibleController
+}
+
+// ExampleWithProber_oidc shows how an external OIDC identity provider is wired up: WithProber
+// swaps in an oidcDiscoveryProber so the controller checks the provider's discovery document
+// instead of an HTTPS-healthz endpoint the provider doesn't expose.
+func ExampleWithProber_oidc() {
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+
+	controller := NewEndpointAccessibleController(
+		"OIDCIdentityProvider",
+		operatorClient,
+		func() ([]string, error) { return []string{"https://issuer.example.com"}, nil },
+		nil,
+		events.NewInMemoryRecorder("OIDCIdentityProviderEndpointAccessibleController"),
+		WithProber(&oidcDiscoveryProber{}),
+	)
+
+	fmt.Println(controller.Name())
+	// Output: OIDCIdentityProviderEndpointAccessibleController
+}
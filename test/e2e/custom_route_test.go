@@ -2,9 +2,13 @@ package e2e
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
+	"math/big"
 	"net/http"
 	"reflect"
 	"strings"
@@ -22,7 +26,9 @@ import (
 	"k8s.io/client-go/util/keyutil"
 
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	operatorclient "github.com/openshift/client-go/operator/clientset/versioned"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 
 	e2e "github.com/openshift/cluster-authentication-operator/test/library"
@@ -116,6 +122,141 @@ func TestCustomRouterCerts(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestCustomRouterCertsInvalidSecret verifies that a servingCertKeyPairSecret which exists but
+// is broken - unlike the "secret does not exist" case covered by TestCustomRouterCerts - is
+// rejected with a Degraded condition instead of being silently applied or silently ignored.
+func TestCustomRouterCertsInvalidSecret(t *testing.T) {
+	kubeConfig := e2e.NewClientConfigForTest(t)
+
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	require.NoError(t, err)
+	configClient, err := configclient.NewForConfig(kubeConfig)
+	require.NoError(t, err)
+	operatorClient, err := operatorclient.NewForConfig(kubeConfig)
+	require.NoError(t, err)
+
+	ingressConfig, err := configClient.ConfigV1().Ingresses().Get(context.TODO(), "cluster", metav1.GetOptions{})
+	require.NoError(t, err)
+	hostname := "invalid." + ingressConfig.Spec.Domain
+
+	rootCA := e2e.NewCertificateAuthorityCertificate(t, nil)
+
+	tests := []struct {
+		name   string
+		secret func(t *testing.T) *corev1.Secret
+	}{
+		{
+			name: "expired certificate",
+			secret: func(t *testing.T) *corev1.Secret {
+				return newExpiredTLSSecret(t, hostname)
+			},
+		},
+		{
+			name: "hostname not covered by SANs",
+			secret: func(t *testing.T) *corev1.Secret {
+				server := e2e.NewServerCertificate(t, rootCA, "wrong."+ingressConfig.Spec.Domain)
+				return newTLSSecret(t, server)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret, err := kubeClient.CoreV1().Secrets("openshift-config").Create(context.TODO(), tt.secret(t), metav1.CreateOptions{})
+			require.NoError(t, err)
+			defer func() {
+				err = removeComponentRoute(t, configClient, "openshift-authentication", "oauth-openshift")
+				require.NoError(t, err)
+				err = kubeClient.CoreV1().Secrets(secret.Namespace).Delete(context.TODO(), secret.Name, metav1.DeleteOptions{})
+				require.NoError(t, err)
+			}()
+
+			err = getAndUpdateComponentRoute(t, configClient, &configv1.ComponentRouteSpec{
+				Namespace: "openshift-authentication",
+				Name:      "oauth-openshift",
+				Hostname:  configv1.Hostname(hostname),
+				ServingCertKeyPairSecret: configv1.SecretNameReference{
+					Name: secret.Name,
+				},
+			})
+			require.NoError(t, err)
+
+			err = checkComponentRouteSecretDegraded(t, operatorClient, "oauth-openshift")
+			require.NoError(t, err)
+		})
+	}
+}
+
+// newExpiredTLSSecret returns a TLS secret whose certificate has NotAfter baked into the signing
+// template rather than set on the parsed struct afterwards, so it is genuinely expired: mutating
+// Certificate.NotAfter post-signing has no effect on the DER actually written to tls.crt.
+func newExpiredTLSSecret(t *testing.T, hostname string) *corev1.Secret {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: hostname},
+		DNSNames:     []string{hostname},
+		NotBefore:    time.Now().Add(-48 * time.Hour),
+		NotAfter:     time.Now().Add(-time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	privateKey, err := keyutil.MarshalPrivateKeyToPEM(key)
+	require.NoError(t, err)
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: strings.ReplaceAll(strings.ToLower(t.Name()), "/", "-") + "-"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"tls.crt": pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: der}),
+			"tls.key": privateKey,
+		},
+	}
+}
+
+func newTLSSecret(t *testing.T, server *e2e.CertificateSpec) *corev1.Secret {
+	t.Helper()
+	privateKey, err := keyutil.MarshalPrivateKeyToPEM(server.PrivateKey)
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: strings.ReplaceAll(strings.ToLower(t.Name()), "/", "-") + "-"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"tls.crt": pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: server.Certificate.Raw}),
+			"tls.key": privateKey,
+		},
+	}
+}
+
+// checkComponentRouteSecretDegraded polls the authentication operator status for the
+// "<componentRouteName>CustomRouteSecretDegraded" condition going True, which the customroute
+// controller sets when the referenced servingCertKeyPairSecret fails validation.
+func checkComponentRouteSecretDegraded(t *testing.T, operatorClient *operatorclient.Clientset, componentRouteName string) error {
+	t.Helper()
+	conditionType := componentRouteName + "CustomRouteSecretDegraded"
+	return wait.PollImmediate(time.Second, time.Minute, func() (bool, error) {
+		authentication, err := operatorClient.OperatorV1().Authentications().Get(context.TODO(), "cluster", metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			t.Logf("Unable to retrieve authentication operator status: %v", err)
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, condition := range authentication.Status.Conditions {
+			if condition.Type == conditionType {
+				return condition.Status == operatorv1.ConditionTrue, nil
+			}
+		}
+		return false, nil
+	})
+}
+
 func pollForCustomServingCertificates(t *testing.T, hostname string, certificate *x509.Certificate) error {
 	t.Helper()
 	transport := &http.Transport{